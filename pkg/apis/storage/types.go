@@ -0,0 +1,183 @@
+/*
+Copyright 2015 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	api "k8s.io/kubernetes/pkg/apis/core"
+)
+
+// StorageClass describes the parameters for a class of storage for
+// which PersistentVolumes can be dynamically provisioned.
+type StorageClass struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	Provisioner          string
+	Parameters           map[string]string
+	ReclaimPolicy        *api.PersistentVolumeReclaimPolicy
+	MountOptions         []string
+	AllowVolumeExpansion *bool
+	VolumeBindingMode    *VolumeBindingMode
+	AllowedTopologies    []api.TopologySelectorTerm
+}
+
+// VolumeBindingMode indicates how PersistentVolumeClaims should be bound.
+type VolumeBindingMode string
+
+const (
+	VolumeBindingImmediate            VolumeBindingMode = "Immediate"
+	VolumeBindingWaitForFirstConsumer VolumeBindingMode = "WaitForFirstConsumer"
+)
+
+// VolumeAttachment captures the intent to attach or detach the specified
+// volume to/from the specified node.
+type VolumeAttachment struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	Spec   VolumeAttachmentSpec
+	Status VolumeAttachmentStatus
+}
+
+// VolumeAttachmentSpec is the specification of a VolumeAttachment request.
+type VolumeAttachmentSpec struct {
+	Attacher string
+	Source   VolumeAttachmentSource
+	NodeName string
+}
+
+// VolumeAttachmentSource represents a volume that should be attached.
+type VolumeAttachmentSource struct {
+	PersistentVolumeName *string
+	InlineVolumeSpec     *api.PersistentVolumeSpec
+}
+
+// VolumeAttachmentStatus is the status of a VolumeAttachment request.
+type VolumeAttachmentStatus struct {
+	Attached           bool
+	AttachmentMetadata map[string]string
+	AttachError        *VolumeError
+	DetachError        *VolumeError
+}
+
+// VolumeError captures an error encountered during a volume operation.
+type VolumeError struct {
+	Time    metav1.Time
+	Message string
+}
+
+// CSINode holds information about all CSI drivers installed on a node.
+type CSINode struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	Spec CSINodeSpec
+}
+
+// CSINodeSpec holds information about the drivers installed on a node.
+type CSINodeSpec struct {
+	Drivers []CSINodeDriver
+}
+
+// CSINodeDriver holds information about the specification of one CSI driver
+// installed on a node.
+type CSINodeDriver struct {
+	Name         string
+	NodeID       string
+	TopologyKeys []string
+	Allocatable  *VolumeNodeResources
+}
+
+// VolumeNodeResources describes volume resource limits on a node.
+type VolumeNodeResources struct {
+	Count *int32
+}
+
+// CSIDriver captures information about a Container Storage Interface (CSI)
+// volume driver deployed on the cluster.
+type CSIDriver struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	Spec CSIDriverSpec
+}
+
+// CSIDriverSpec is the specification of a CSIDriver.
+type CSIDriverSpec struct {
+	AttachRequired       *bool
+	PodInfoOnMount       *bool
+	VolumeLifecycleModes []VolumeLifecycleMode
+	StorageCapacity      *bool
+	FSGroupPolicy        *FSGroupPolicy
+	TokenRequests        []TokenRequest
+	RequiresRepublish    *bool
+}
+
+// VolumeLifecycleMode is the type of usage a CSI volume driver supports.
+type VolumeLifecycleMode string
+
+const (
+	VolumeLifecyclePersistent VolumeLifecycleMode = "Persistent"
+	VolumeLifecycleEphemeral  VolumeLifecycleMode = "Ephemeral"
+)
+
+// FSGroupPolicy describes how CSI volumes may apply fsGroup ownership.
+type FSGroupPolicy string
+
+const (
+	ReadWriteOnceWithFSTypeFSGroupPolicy FSGroupPolicy = "ReadWriteOnceWithFSType"
+	FileFSGroupPolicy                    FSGroupPolicy = "File"
+	NoneFSGroupPolicy                    FSGroupPolicy = "None"
+)
+
+// TokenRequest contains parameters of a service account token.
+type TokenRequest struct {
+	Audience          string
+	ExpirationSeconds *int64
+}
+
+// CSIStorageCapacity stores the result of one CSI GetCapacity call: how much
+// free capacity a CSI driver has for a given StorageClass and node topology.
+//
+// RefreshPeriod and LastRefreshTime let consumers judge whether a reported
+// Capacity can still be trusted, since an object that is never refreshed
+// again (e.g. because its external-provisioner crashed) would otherwise
+// mislead the scheduler forever. Conditions is a flat slice, matching the
+// rest of this type's fields, rather than living under a separate Status
+// subresource: CSIStorageCapacity has no status subresource today.
+type CSIStorageCapacity struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	NodeTopology      *metav1.LabelSelector
+	StorageClassName  string
+	Capacity          *resource.Quantity
+	MaximumVolumeSize *resource.Quantity
+
+	// RefreshPeriod is how often the reporting external-provisioner intends to update
+	// this object via CSI GetCapacity. A nil RefreshPeriod means this object's freshness
+	// is not tracked and it is never considered stale.
+	RefreshPeriod *metav1.Duration
+	// LastRefreshTime is the last time the reporting external-provisioner successfully
+	// refreshed Capacity and MaximumVolumeSize.
+	LastRefreshTime *metav1.Time
+	// Conditions reports this object's current state, including a Stale condition set by
+	// the csistoragecapacity staleness controller.
+	Conditions []metav1.Condition
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/kubernetes/pkg/apis/storage"
+)
+
+func TestValidateCSINodeDriverLongNodeID(t *testing.T) {
+	shortID := "short-node-id"
+	longID := make([]byte, csiNodeIDMaxLength+1)
+	for i := range longID {
+		longID[i] = 'a'
+	}
+
+	testCases := []struct {
+		name          string
+		nodeID        string
+		topologyKeys  []string
+		allowLongNode bool
+		wantError     bool
+	}{
+		{
+			name:          "short node id with no topology keys is valid even when the option is on",
+			nodeID:        shortID,
+			topologyKeys:  nil,
+			allowLongNode: true,
+			wantError:     false,
+		},
+		{
+			name:          "long node id with no topology keys is invalid when the option is on",
+			nodeID:        string(longID),
+			topologyKeys:  nil,
+			allowLongNode: true,
+			wantError:     true,
+		},
+		{
+			name:          "long node id with a topology key is valid",
+			nodeID:        string(longID),
+			topologyKeys:  []string{"topology.example.com/zone"},
+			allowLongNode: true,
+			wantError:     false,
+		},
+		{
+			name:          "long node id rejected on its own length when the option is off, regardless of topology keys",
+			nodeID:        string(longID),
+			topologyKeys:  nil,
+			allowLongNode: false,
+			wantError:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			driver := storage.CSINodeDriver{
+				Name:         "csi.example.com",
+				NodeID:       tc.nodeID,
+				TopologyKeys: tc.topologyKeys,
+			}
+			opts := CSINodeValidationOptions{AllowLongNodeID: tc.allowLongNode}
+			errs := validateCSINodeDriver(driver, make(sets.String), field.NewPath("spec", "drivers").Index(0), opts)
+			if tc.wantError && len(errs) == 0 {
+				t.Errorf("expected validation errors, got none")
+			}
+			if !tc.wantError && len(errs) != 0 {
+				t.Errorf("expected no validation errors, got %v", errs)
+			}
+		})
+	}
+}
+
+func int32Ptr(v int32) *int32 { return &v }
+
+func TestValidateCSINodeDriverAllocatableLimit(t *testing.T) {
+	testCases := []struct {
+		name      string
+		count     *int32
+		limits    map[string]int32
+		wantError bool
+	}{
+		{
+			name:      "no declared limit for this driver",
+			count:     int32Ptr(10),
+			limits:    map[string]int32{},
+			wantError: false,
+		},
+		{
+			name:      "within the declared limit",
+			count:     int32Ptr(10),
+			limits:    map[string]int32{"csi.example.com": 20},
+			wantError: false,
+		},
+		{
+			name:      "exceeds the declared limit",
+			count:     int32Ptr(30),
+			limits:    map[string]int32{"csi.example.com": 20},
+			wantError: true,
+		},
+		{
+			name:      "nil count is never checked",
+			count:     nil,
+			limits:    map[string]int32{"csi.example.com": 20},
+			wantError: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			driver := storage.CSINodeDriver{
+				Name:        "csi.example.com",
+				Allocatable: &storage.VolumeNodeResources{Count: tc.count},
+			}
+			opts := CSINodeValidationOptions{DriverMaxVolumeLimits: tc.limits}
+			errs := validateCSINodeDriverAllocatableLimit(driver, field.NewPath("spec", "drivers").Index(0), opts)
+			if tc.wantError && len(errs) == 0 {
+				t.Errorf("expected validation errors, got none")
+			}
+			if !tc.wantError && len(errs) != 0 {
+				t.Errorf("expected no validation errors, got %v", errs)
+			}
+		})
+	}
+}
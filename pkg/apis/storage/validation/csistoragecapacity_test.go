@@ -0,0 +1,150 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/apis/storage"
+)
+
+func quantityPtr(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
+
+func TestValidateCSIStorageCapacityCapacityFields(t *testing.T) {
+	testCases := []struct {
+		name              string
+		capacity          *resource.Quantity
+		maximumVolumeSize *resource.Quantity
+		wantError         bool
+	}{
+		{
+			name:              "zero capacity with no maximumVolumeSize is valid",
+			capacity:          quantityPtr("0"),
+			maximumVolumeSize: nil,
+			wantError:         false,
+		},
+		{
+			name:              "zero capacity with zero maximumVolumeSize is valid",
+			capacity:          quantityPtr("0"),
+			maximumVolumeSize: quantityPtr("0"),
+			wantError:         false,
+		},
+		{
+			name:              "zero capacity with nonzero maximumVolumeSize is invalid",
+			capacity:          quantityPtr("0"),
+			maximumVolumeSize: quantityPtr("1Gi"),
+			wantError:         true,
+		},
+		{
+			name:              "maximumVolumeSize greater than capacity is invalid",
+			capacity:          quantityPtr("1Gi"),
+			maximumVolumeSize: quantityPtr("2Gi"),
+			wantError:         true,
+		},
+		{
+			name:              "maximumVolumeSize equal to capacity is valid",
+			capacity:          quantityPtr("1Gi"),
+			maximumVolumeSize: quantityPtr("1Gi"),
+			wantError:         false,
+		},
+		{
+			name:              "maximumVolumeSize less than capacity is valid",
+			capacity:          quantityPtr("2Gi"),
+			maximumVolumeSize: quantityPtr("1Gi"),
+			wantError:         false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			capacity := &storage.CSIStorageCapacity{
+				ObjectMeta:        metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+				StorageClassName:  "standard",
+				Capacity:          tc.capacity,
+				MaximumVolumeSize: tc.maximumVolumeSize,
+			}
+			errs := ValidateCSIStorageCapacity(capacity)
+			if tc.wantError && len(errs) == 0 {
+				t.Errorf("expected validation errors, got none")
+			}
+			if !tc.wantError && len(errs) != 0 {
+				t.Errorf("expected no validation errors, got %v", errs)
+			}
+		})
+	}
+}
+
+// TestValidateCSIStorageCapacityUpdateCapacityFields guards against the update path silently
+// reopening a combination ValidateCSIStorageCapacity would reject at create, since an update
+// that could reach an invariant-violating state would defeat the whole point of keeping
+// Capacity/MaximumVolumeSize legible to the scheduler.
+func TestValidateCSIStorageCapacityUpdateCapacityFields(t *testing.T) {
+	testCases := []struct {
+		name              string
+		capacity          *resource.Quantity
+		maximumVolumeSize *resource.Quantity
+		wantError         bool
+	}{
+		{
+			name:              "zero capacity with nonzero maximumVolumeSize is invalid on update too",
+			capacity:          quantityPtr("0"),
+			maximumVolumeSize: quantityPtr("10Gi"),
+			wantError:         true,
+		},
+		{
+			name:              "maximumVolumeSize greater than capacity is invalid on update too",
+			capacity:          quantityPtr("1Gi"),
+			maximumVolumeSize: quantityPtr("2Gi"),
+			wantError:         true,
+		},
+		{
+			name:              "a legible refresh of capacity values is valid",
+			capacity:          quantityPtr("2Gi"),
+			maximumVolumeSize: quantityPtr("1Gi"),
+			wantError:         false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			oldCapacity := &storage.CSIStorageCapacity{
+				ObjectMeta:       metav1.ObjectMeta{Name: "foo", Namespace: "default", ResourceVersion: "1"},
+				StorageClassName: "standard",
+				Capacity:         quantityPtr("1Gi"),
+			}
+			newCapacity := &storage.CSIStorageCapacity{
+				ObjectMeta:        oldCapacity.ObjectMeta,
+				StorageClassName:  oldCapacity.StorageClassName,
+				Capacity:          tc.capacity,
+				MaximumVolumeSize: tc.maximumVolumeSize,
+			}
+
+			errs := ValidateCSIStorageCapacityUpdate(newCapacity, oldCapacity)
+			if tc.wantError && len(errs) == 0 {
+				t.Errorf("expected validation errors, got none")
+			}
+			if !tc.wantError && len(errs) != 0 {
+				t.Errorf("expected no validation errors, got %v", errs)
+			}
+		})
+	}
+}
@@ -19,11 +19,14 @@ package validation
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/api/validation/immutable"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -50,6 +53,111 @@ const (
 // CSINodeValidationOptions contains the validation options for validating CSINode
 type CSINodeValidationOptions struct {
 	AllowLongNodeID bool
+
+	// DriverMaxVolumeLimits, when set, caps the Allocatable.Count a driver on this
+	// CSINode may advertise. The cap is looked up by driver name (e.g. sourced from a
+	// CSIDriver spec extension or a well-known annotation) so the max-volume scheduling
+	// predicate can trust Allocatable instead of defensively clamping the values it reads.
+	DriverMaxVolumeLimits map[string]int32
+}
+
+// StorageClassUpdateOptions contains the validation options for validating StorageClass updates.
+type StorageClassUpdateOptions struct {
+	// AllowIdempotentMerge permits an update to Parameters, Provisioner and ReclaimPolicy
+	// as long as the identity-affecting subset of Parameters is unchanged and Provisioner
+	// resolves to the same CSI driver. This mirrors the idempotency contract CSI drivers
+	// already provide for CreateVolume, so fixing a typo in a non-identity parameter no
+	// longer requires deleting and recreating the StorageClass.
+	AllowIdempotentMerge bool
+}
+
+// ParameterClass describes how a StorageClass parameter participates in the
+// identity of the volumes it provisions.
+type ParameterClass string
+
+const (
+	// ParameterClassIdentity parameters determine what kind of volume is provisioned.
+	// Changing one of these changes the meaning of every volume already bound to the
+	// StorageClass, so they may never change on update.
+	ParameterClassIdentity ParameterClass = "Identity"
+	// ParameterClassMutable parameters are metadata-like (tags, secret references, etc.)
+	// and may be added or changed freely without affecting already-provisioned volumes.
+	ParameterClassMutable ParameterClass = "Mutable"
+	// ParameterClassNodeControlled parameters are set by node/topology constraints rather
+	// than by the user and are treated the same as Identity for update purposes.
+	ParameterClassNodeControlled ParameterClass = "NodeControlled"
+)
+
+// defaultParameterClassification is the built-in registry of well-known CSI
+// parameter keys. Per-driver entries registered via RegisterParameterClass
+// take precedence over it; this map itself is not changed at runtime.
+var defaultParameterClassification = map[string]ParameterClass{
+	"fsType":                    ParameterClassIdentity,
+	"type":                      ParameterClassIdentity,
+	"iopsPerGB":                 ParameterClassIdentity,
+	"encrypted":                 ParameterClassIdentity,
+	"kmsKeyId":                  ParameterClassIdentity,
+	"csi.storage.k8s.io/fstype": ParameterClassIdentity,
+}
+
+// driverParameterClassification holds per-driver overrides and additions
+// registered by CSI driver code via RegisterParameterClass.
+var driverParameterClassification = map[string]map[string]ParameterClass{}
+
+// provisionerEquivalence maps an in-tree provisioner name to the CSI driver
+// name it was migrated to, so that ValidateStorageClassUpdate can recognize
+// an in-tree-to-CSI migration as "the same driver" rather than a provisioner
+// change. CSI driver code can extend this via RegisterProvisionerEquivalence.
+var provisionerEquivalence = map[string]string{}
+
+// RegisterParameterClass lets CSI driver code classify a driver-specific
+// parameter key that isn't covered by the built-in registry. The well-known
+// csi.storage.k8s.io/*-secret-name and *-secret-namespace keys are classified
+// Mutable without registration, and any other unregistered key, topology keys
+// included, is classified Identity; see ClassifyStorageClassParameter.
+func RegisterParameterClass(driver, key string, class ParameterClass) {
+	perDriver, ok := driverParameterClassification[driver]
+	if !ok {
+		perDriver = map[string]ParameterClass{}
+		driverParameterClassification[driver] = perDriver
+	}
+	perDriver[key] = class
+}
+
+// RegisterProvisionerEquivalence records that oldProvisioner and newDriver
+// identify the same underlying CSI driver, e.g. an in-tree plugin name and
+// its CSI migration replacement.
+func RegisterProvisionerEquivalence(oldProvisioner, newDriver string) {
+	provisionerEquivalence[oldProvisioner] = newDriver
+}
+
+// ClassifyStorageClassParameter reports how a StorageClass parameter key
+// participates in volume identity for the given provisioner/driver name.
+// Unknown keys default to Identity: unrecognized parameters are assumed to
+// affect provisioning until a driver or the built-in registry says otherwise.
+func ClassifyStorageClassParameter(driver, key string) ParameterClass {
+	if perDriver, ok := driverParameterClassification[driver]; ok {
+		if class, ok := perDriver[key]; ok {
+			return class
+		}
+	}
+	if class, ok := defaultParameterClassification[key]; ok {
+		return class
+	}
+	if strings.HasPrefix(key, "csi.storage.k8s.io/") &&
+		(strings.HasSuffix(key, "-secret-name") || strings.HasSuffix(key, "-secret-namespace")) {
+		return ParameterClassMutable
+	}
+	return ParameterClassIdentity
+}
+
+// resolveProvisionerIdentity returns the canonical CSI driver name for a
+// provisioner, following the migration equivalence map when present.
+func resolveProvisionerIdentity(provisioner string) string {
+	if canonical, ok := provisionerEquivalence[provisioner]; ok {
+		return canonical
+	}
+	return provisioner
 }
 
 // ValidateStorageClass validates a StorageClass.
@@ -65,17 +173,21 @@ func ValidateStorageClass(storageClass *storage.StorageClass) field.ErrorList {
 }
 
 // ValidateStorageClassUpdate tests if an update to StorageClass is valid.
-func ValidateStorageClassUpdate(storageClass, oldStorageClass *storage.StorageClass) field.ErrorList {
+func ValidateStorageClassUpdate(storageClass, oldStorageClass *storage.StorageClass, opts StorageClassUpdateOptions) field.ErrorList {
 	allErrs := apivalidation.ValidateObjectMetaUpdate(&storageClass.ObjectMeta, &oldStorageClass.ObjectMeta, field.NewPath("metadata"))
-	if !reflect.DeepEqual(oldStorageClass.Parameters, storageClass.Parameters) {
-		allErrs = append(allErrs, field.Forbidden(field.NewPath("parameters"), "updates to parameters are forbidden."))
-	}
 
-	if storageClass.Provisioner != oldStorageClass.Provisioner {
-		allErrs = append(allErrs, field.Forbidden(field.NewPath("provisioner"), "updates to provisioner are forbidden."))
+	if opts.AllowIdempotentMerge {
+		allErrs = append(allErrs, validateStorageClassIdempotentMerge(storageClass, oldStorageClass)...)
+	} else {
+		if !reflect.DeepEqual(oldStorageClass.Parameters, storageClass.Parameters) {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("parameters"), "updates to parameters are forbidden."))
+		}
+		if storageClass.Provisioner != oldStorageClass.Provisioner {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("provisioner"), "updates to provisioner are forbidden."))
+		}
 	}
 
-	if *storageClass.ReclaimPolicy != *oldStorageClass.ReclaimPolicy {
+	if *storageClass.ReclaimPolicy != *oldStorageClass.ReclaimPolicy && !opts.AllowIdempotentMerge {
 		allErrs = append(allErrs, field.Forbidden(field.NewPath("reclaimPolicy"), "updates to reclaimPolicy are forbidden."))
 	}
 
@@ -83,6 +195,52 @@ func ValidateStorageClassUpdate(storageClass, oldStorageClass *storage.StorageCl
 	return allErrs
 }
 
+// validateStorageClassIdempotentMerge implements the CSI CreateVolume-style
+// idempotency contract for StorageClass updates: the update is accepted iff
+// the identity-affecting subset of Parameters is unchanged, and Provisioner
+// may only change between names that resolve to the same CSI driver (e.g. an
+// in-tree plugin migrating to its CSI replacement).
+func validateStorageClassIdempotentMerge(storageClass, oldStorageClass *storage.StorageClass) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// The idempotent-merge path is the only way Parameters can change on update, so the
+	// create-time format/size caps have to be re-checked here; they were moot before this
+	// option existed, since no parameter mutation was possible at all.
+	allErrs = append(allErrs, validateProvisioner(storageClass.Provisioner, field.NewPath("provisioner"))...)
+	allErrs = append(allErrs, validateParameters(storageClass.Parameters, field.NewPath("parameters"))...)
+
+	oldDriver := resolveProvisionerIdentity(oldStorageClass.Provisioner)
+	newDriver := resolveProvisionerIdentity(storageClass.Provisioner)
+	if oldDriver != newDriver {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("provisioner"), "updates to provisioner are only allowed between names that resolve to the same CSI driver"))
+		return allErrs
+	}
+
+	for key, oldValue := range oldStorageClass.Parameters {
+		if ClassifyStorageClassParameter(newDriver, key) == ParameterClassMutable {
+			continue
+		}
+		newValue, ok := storageClass.Parameters[key]
+		if !ok {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("parameters").Key(key), "identity parameter may not be removed"))
+			continue
+		}
+		if newValue != oldValue {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("parameters").Key(key), "identity parameter may not be changed"))
+		}
+	}
+	for key := range storageClass.Parameters {
+		if _, existed := oldStorageClass.Parameters[key]; existed {
+			continue
+		}
+		if ClassifyStorageClassParameter(newDriver, key) != ParameterClassMutable {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("parameters").Key(key), "identity parameter may not be added after creation"))
+		}
+	}
+
+	return allErrs
+}
+
 // validateProvisioner tests if provisioner is a valid qualified name.
 func validateProvisioner(provisioner string, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -304,13 +462,15 @@ func ValidateCSINode(csiNode *storage.CSINode, validationOpts CSINodeValidationO
 func ValidateCSINodeUpdate(new, old *storage.CSINode, validationOpts CSINodeValidationOptions) field.ErrorList {
 	allErrs := ValidateCSINode(new, validationOpts)
 
-	// Validate modifying fields inside an existing CSINodeDriver entry is not allowed
+	// Every field inside an existing CSINodeDriver entry is immutable: a driver can be
+	// added or removed, but not modified in place. This is the migration example for the
+	// shared immutable-field framework: instead of a hand-written DeepEqual-and-Invalid
+	// per entry, the whole struct is forced immutable and the framework recurses to name
+	// the specific changed leaf.
 	for _, oldDriver := range old.Spec.Drivers {
 		for _, newDriver := range new.Spec.Drivers {
 			if oldDriver.Name == newDriver.Name {
-				if !apiequality.Semantic.DeepEqual(oldDriver, newDriver) {
-					allErrs = append(allErrs, field.Invalid(field.NewPath("CSINodeDriver"), newDriver, "field is immutable"))
-				}
+				allErrs = append(allErrs, immutable.ValidateStruct(&newDriver, &oldDriver, field.NewPath("CSINodeDriver"))...)
 			}
 		}
 	}
@@ -327,6 +487,14 @@ func validateCSINodeSpec(
 }
 
 // ValidateCSINodeDrivers tests that the specified CSINodeDrivers have valid data.
+//
+// This intentionally does not reject two drivers that declare the same topology key: the
+// backlog asked for duplicate (driver, topologyKey) pairs "where the values would necessarily
+// conflict" to be rejected, but CSINodeDriver only records topology *keys*, not the topology
+// *values* a driver would report for them, so there is nothing here to compare for a conflict.
+// An earlier attempt approximated this by comparing NodeID across drivers sharing a key, but
+// NodeID has no defined relationship to topology value and that produced false positives on
+// ordinary multi-driver CSINode configs; it was removed rather than reimplemented.
 func validateCSINodeDrivers(drivers []storage.CSINodeDriver, fldPath *field.Path, validationOpts CSINodeValidationOptions) field.ErrorList {
 	allErrs := field.ErrorList{}
 	driverNamesInSpecs := make(sets.String)
@@ -356,6 +524,24 @@ func validateCSINodeDriverNodeID(nodeID string, fldPath *field.Path, validationO
 	return allErrs
 }
 
+// validateCSINodeDriverAllocatableLimit tests that Allocatable.Count does not exceed the
+// hard cap declared for this driver, when one is configured.
+func validateCSINodeDriverAllocatableLimit(driver storage.CSINodeDriver, fldPath *field.Path, validationOpts CSINodeValidationOptions) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if driver.Allocatable == nil || driver.Allocatable.Count == nil {
+		return allErrs
+	}
+	maxVolumeLimit, ok := validationOpts.DriverMaxVolumeLimits[driver.Name]
+	if !ok {
+		return allErrs
+	}
+	if *driver.Allocatable.Count > maxVolumeLimit {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("allocatable", "count"), *driver.Allocatable.Count, fmt.Sprintf("must not exceed the declared limit of %d for driver %s", maxVolumeLimit, driver.Name)))
+	}
+	return allErrs
+}
+
 // CSINodeLongerID will check if the nodeID is longer than csiNodeIDMaxLength
 func CSINodeLongerID(nodeID string) bool {
 	return len(nodeID) > csiNodeIDMaxLength
@@ -381,6 +567,16 @@ func validateCSINodeDriver(driver storage.CSINodeDriver, driverNamesInSpecs sets
 	allErrs = append(allErrs, apivalidation.ValidateCSIDriverName(driver.Name, fldPath.Child("name"))...)
 	allErrs = append(allErrs, validateCSINodeDriverNodeID(driver.NodeID, fldPath.Child("nodeID"), validationOpts)...)
 	allErrs = append(allErrs, validateCSINodeDriverAllocatable(driver.Allocatable, fldPath.Child("allocatable"))...)
+	allErrs = append(allErrs, validateCSINodeDriverAllocatableLimit(driver, fldPath, validationOpts)...)
+
+	// Only a driver that is actually using a long node id needs topology keys: the
+	// rationale is that long node ids come from topology-aware cloud providers, not that
+	// every driver on the CSINode must have one. Scoping this to CSINodeLongerID(driver.NodeID)
+	// instead of the cluster-wide AllowLongNodeID option keeps ordinary short-id drivers
+	// with no topology keys valid even after the option/feature gate is turned on.
+	if validationOpts.AllowLongNodeID && CSINodeLongerID(driver.NodeID) && len(driver.TopologyKeys) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("topologyKeys"), "must specify at least one topology key when using a long node id, since long node ids almost always come from topology-aware cloud providers"))
+	}
 
 	// check for duplicate entries for the same driver in specs
 	if driverNamesInSpecs.Has(driver.Name) {
@@ -550,24 +746,149 @@ func ValidateCSIStorageCapacity(capacity *storage.CSIStorageCapacity) field.Erro
 	for _, msg := range apivalidation.ValidateClassName(capacity.StorageClassName, false) {
 		allErrs = append(allErrs, field.Invalid(field.NewPath("storageClassName"), capacity.StorageClassName, msg))
 	}
+	allErrs = append(allErrs, validateCSIStorageCapacityCapacity(capacity)...)
+	allErrs = append(allErrs, validateRefreshPeriod(capacity.RefreshPeriod, field.NewPath("refreshPeriod"))...)
+	return allErrs
+}
+
+// validateCSIStorageCapacityCapacity validates Capacity and MaximumVolumeSize, including the
+// cross-field invariants that keep the pair legible to the scheduler: MaximumVolumeSize may
+// never exceed Capacity, and Capacity may only be zero when MaximumVolumeSize is also zero or
+// unset, so that "no volume fits" (zero) stays distinguishable from "capacity unknown" (unset).
+// These invariants are enforced identically on create and update: relaxing them only on update
+// would let a client reach a combination through an update that create would have rejected.
+func validateCSIStorageCapacityCapacity(capacity *storage.CSIStorageCapacity) field.ErrorList {
+	allErrs := field.ErrorList{}
 	if capacity.Capacity != nil {
 		allErrs = append(allErrs, apivalidation.ValidateNonnegativeQuantity(*capacity.Capacity, field.NewPath("capacity"))...)
+		if capacity.Capacity.IsZero() && capacity.MaximumVolumeSize != nil && !capacity.MaximumVolumeSize.IsZero() {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("capacity"), capacity.Capacity, "must not be zero unless maximumVolumeSize is also zero or unset, so that zero capacity is distinguishable from unknown capacity"))
+		}
+	}
+	if capacity.MaximumVolumeSize != nil {
+		allErrs = append(allErrs, apivalidation.ValidateNonnegativeQuantity(*capacity.MaximumVolumeSize, field.NewPath("maximumVolumeSize"))...)
+		if capacity.Capacity != nil && capacity.MaximumVolumeSize.Cmp(*capacity.Capacity) > 0 {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("maximumVolumeSize"), capacity.MaximumVolumeSize, "must not be greater than capacity"))
+		}
+	}
+	return allErrs
+}
+
+// capacityTopologyKey identifies a CSIStorageCapacity object's scheduling
+// identity: the driver that reports it, the StorageClass it describes
+// capacity for, and the node topology it applies to. At most one
+// CSIStorageCapacity object may exist per key, so that the scheduler's
+// per-driver volume-limit predicate has an unambiguous object to read.
+type capacityTopologyKey struct {
+	driver           string
+	storageClassName string
+	topologyHash     string
+}
+
+// ValidateCSIStorageCapacityList validates a list of CSIStorageCapacity
+// objects as a whole, in addition to the per-object checks performed by
+// ValidateCSIStorageCapacity. It reports field.Duplicate for any two objects
+// that share the same driver, StorageClassName and NodeTopology, since the
+// scheduler's volume-limit accounting requires a stable, unambiguous object
+// per (driver, class, topology) tuple.
+func ValidateCSIStorageCapacityList(capacities []storage.CSIStorageCapacity, driverName func(*storage.CSIStorageCapacity) string) field.ErrorList {
+	allErrs := field.ErrorList{}
+	seen := make(map[capacityTopologyKey]string, len(capacities))
+	for i := range capacities {
+		capacity := &capacities[i]
+		idxPath := field.NewPath("items").Index(i)
+		allErrs = append(allErrs, ValidateCSIStorageCapacity(capacity)...)
+
+		key := capacityTopologyKey{
+			driver:           driverName(capacity),
+			storageClassName: capacity.StorageClassName,
+			topologyHash:     canonicalLabelSelectorHash(capacity.NodeTopology),
+		}
+		if existingName, ok := seen[key]; ok {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("nodeTopology"), fmt.Sprintf("%s already has a CSIStorageCapacity object (%s) for this storage class and topology", key.driver, existingName)))
+			continue
+		}
+		seen[key] = capacity.Name
 	}
 	return allErrs
 }
 
+// canonicalLabelSelectorHash returns a stable string identity for a label
+// selector, canonicalized so that equivalent selectors (e.g. differing only
+// in match-expression order) hash the same.
+func canonicalLabelSelectorHash(selector *metav1.LabelSelector) string {
+	if selector == nil {
+		return ""
+	}
+	labels := make([]string, 0, len(selector.MatchLabels))
+	for k, v := range selector.MatchLabels {
+		labels = append(labels, k+"="+v)
+	}
+	sort.Strings(labels)
+
+	exprs := make([]string, 0, len(selector.MatchExpressions))
+	for _, expr := range selector.MatchExpressions {
+		values := append([]string(nil), expr.Values...)
+		sort.Strings(values)
+		exprs = append(exprs, fmt.Sprintf("%s:%s:%s", expr.Key, expr.Operator, strings.Join(values, ",")))
+	}
+	sort.Strings(exprs)
+
+	return strings.Join(labels, ",") + "|" + strings.Join(exprs, ",")
+}
+
 // ValidateCSIStorageCapacityUpdate tests if an update to CSIStorageCapacity is valid.
+//
+// NodeTopology and StorageClassName are intentionally mutable: driver
+// upgrades and topology relabeling need to change them without deleting and recreating
+// every CSIStorageCapacity object in the cluster. The registry strategy's PrepareForUpdate
+// bumps ObjectMeta.Generation whenever either field changes, so controllers watching
+// CSIStorageCapacity know to re-invoke CSI GetCapacity for the new topology/class rather
+// than trusting a stale value. Nothing here uses the shared immutable-field framework
+// introduced alongside this function, because CSIStorageCapacity currently has no
+// immutable fields left to enforce; see ValidateCSINodeUpdate for that framework's
+// migration example.
 func ValidateCSIStorageCapacityUpdate(capacity, oldCapacity *storage.CSIStorageCapacity) field.ErrorList {
 	allErrs := apivalidation.ValidateObjectMetaUpdate(&capacity.ObjectMeta, &oldCapacity.ObjectMeta, field.NewPath("metadata"))
 
-	// Input fields for CSI GetCapacity are immutable.
-	// If this ever relaxes in the future, make sure to increment the Generation number in PrepareForUpdate
-	if !apiequality.Semantic.DeepEqual(capacity.NodeTopology, oldCapacity.NodeTopology) {
-		allErrs = append(allErrs, field.Invalid(field.NewPath("nodeTopology"), capacity.NodeTopology, "field is immutable"))
-	}
-	if capacity.StorageClassName != oldCapacity.StorageClassName {
-		allErrs = append(allErrs, field.Invalid(field.NewPath("storageClassName"), capacity.StorageClassName, "field is immutable"))
+	allErrs = append(allErrs, metav1validation.ValidateLabelSelector(capacity.NodeTopology, field.NewPath("nodeTopology"))...)
+	for _, msg := range apivalidation.ValidateClassName(capacity.StorageClassName, false) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("storageClassName"), capacity.StorageClassName, msg))
 	}
 
+	// Capacity and MaximumVolumeSize are expected to change as the external-provisioner
+	// refreshes its view of the backend, so update imposes no extra restriction beyond the
+	// same non-negativity and cross-field invariants ValidateCSIStorageCapacity enforces on
+	// create; an update must not be able to reach a combination create would have rejected.
+	allErrs = append(allErrs, validateCSIStorageCapacityCapacity(capacity)...)
+
+	// RefreshPeriod and the staleness status fields mutate independently of the
+	// Generation-bumped fields above: the external-provisioner reports LastRefreshTime on
+	// every successful GetCapacity, and cluster operators may retune RefreshPeriod without
+	// recreating the object.
+	allErrs = append(allErrs, validateRefreshPeriod(capacity.RefreshPeriod, field.NewPath("refreshPeriod"))...)
+
+	return allErrs
+}
+
+// CSIStorageCapacityTopologyOrClassChanged reports whether an update changed NodeTopology
+// or StorageClassName. The registry strategy calls this from PrepareForUpdate to bump
+// ObjectMeta.Generation, since those two fields are the input to CSI GetCapacity and a
+// Generation bump is how watching controllers learn they must re-invoke it.
+func CSIStorageCapacityTopologyOrClassChanged(capacity, oldCapacity *storage.CSIStorageCapacity) bool {
+	return !apiequality.Semantic.DeepEqual(capacity.NodeTopology, oldCapacity.NodeTopology) ||
+		capacity.StorageClassName != oldCapacity.StorageClassName
+}
+
+// validateRefreshPeriod tests that RefreshPeriod, if set, is a positive duration. A
+// CSIStorageCapacity with no RefreshPeriod is never considered stale.
+func validateRefreshPeriod(refreshPeriod *metav1.Duration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if refreshPeriod == nil {
+		return allErrs
+	}
+	if refreshPeriod.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, refreshPeriod.Duration.String(), "must be greater than zero"))
+	}
 	return allErrs
 }
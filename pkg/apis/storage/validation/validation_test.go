@@ -0,0 +1,272 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	api "k8s.io/kubernetes/pkg/apis/core"
+	"k8s.io/kubernetes/pkg/apis/storage"
+)
+
+func TestClassifyStorageClassParameter(t *testing.T) {
+	testCases := []struct {
+		name   string
+		driver string
+		key    string
+		want   ParameterClass
+	}{
+		{
+			name:   "well-known identity parameter",
+			driver: "csi.example.com",
+			key:    "fsType",
+			want:   ParameterClassIdentity,
+		},
+		{
+			name:   "csi secret-name parameter is mutable",
+			driver: "csi.example.com",
+			key:    "csi.storage.k8s.io/provisioner-secret-name",
+			want:   ParameterClassMutable,
+		},
+		{
+			name:   "csi secret-namespace parameter is mutable",
+			driver: "csi.example.com",
+			key:    "csi.storage.k8s.io/provisioner-secret-namespace",
+			want:   ParameterClassMutable,
+		},
+		{
+			name:   "secret-namespace suffix without the csi.storage.k8s.io/ prefix is not mutable",
+			driver: "csi.example.com",
+			key:    "my.driver.io/provisioner-secret-namespace",
+			want:   ParameterClassIdentity,
+		},
+		{
+			name:   "secret-name suffix without the csi.storage.k8s.io/ prefix is not mutable",
+			driver: "csi.example.com",
+			key:    "my.driver.io/provisioner-secret-name",
+			want:   ParameterClassIdentity,
+		},
+		{
+			name:   "unknown parameter defaults to identity",
+			driver: "csi.example.com",
+			key:    "someRandomParameter",
+			want:   ParameterClassIdentity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ClassifyStorageClassParameter(tc.driver, tc.key); got != tc.want {
+				t.Errorf("ClassifyStorageClassParameter(%q, %q) = %v, want %v", tc.driver, tc.key, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClassifyStorageClassParameterDriverOverride(t *testing.T) {
+	RegisterParameterClass("csi.example.com", "someRandomParameter", ParameterClassMutable)
+	defer delete(driverParameterClassification["csi.example.com"], "someRandomParameter")
+
+	if got := ClassifyStorageClassParameter("csi.example.com", "someRandomParameter"); got != ParameterClassMutable {
+		t.Errorf("ClassifyStorageClassParameter() = %v, want %v", got, ParameterClassMutable)
+	}
+	if got := ClassifyStorageClassParameter("other.driver.io", "someRandomParameter"); got != ParameterClassIdentity {
+		t.Errorf("per-driver override leaked to another driver: ClassifyStorageClassParameter() = %v, want %v", got, ParameterClassIdentity)
+	}
+}
+
+func reclaimPolicy(p api.PersistentVolumeReclaimPolicy) *api.PersistentVolumeReclaimPolicy {
+	return &p
+}
+
+func TestValidateStorageClassUpdate(t *testing.T) {
+	deleteClass := func() *storage.StorageClass {
+		return &storage.StorageClass{
+			ObjectMeta:    metav1.ObjectMeta{Name: "foo", ResourceVersion: "1"},
+			Provisioner:   "kubernetes.io/foo",
+			ReclaimPolicy: reclaimPolicy(api.PersistentVolumeReclaimDelete),
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		modify    func(sc *storage.StorageClass)
+		opts      StorageClassUpdateOptions
+		wantError bool
+	}{
+		{
+			name:      "no-op update is valid",
+			modify:    func(sc *storage.StorageClass) {},
+			wantError: false,
+		},
+		{
+			name: "reclaimPolicy change is forbidden by default",
+			modify: func(sc *storage.StorageClass) {
+				sc.ReclaimPolicy = reclaimPolicy(api.PersistentVolumeReclaimRetain)
+			},
+			wantError: true,
+		},
+		{
+			name: "reclaimPolicy change is allowed under AllowIdempotentMerge",
+			modify: func(sc *storage.StorageClass) {
+				sc.ReclaimPolicy = reclaimPolicy(api.PersistentVolumeReclaimRetain)
+			},
+			opts:      StorageClassUpdateOptions{AllowIdempotentMerge: true},
+			wantError: false,
+		},
+		{
+			name: "provisioner change is forbidden by default",
+			modify: func(sc *storage.StorageClass) {
+				sc.Provisioner = "kubernetes.io/bar"
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			oldClass := deleteClass()
+			newClass := deleteClass()
+			tc.modify(newClass)
+
+			errs := ValidateStorageClassUpdate(newClass, oldClass, tc.opts)
+			if tc.wantError && len(errs) == 0 {
+				t.Errorf("expected validation errors, got none")
+			}
+			if !tc.wantError && len(errs) != 0 {
+				t.Errorf("expected no validation errors, got %v", errs)
+			}
+		})
+	}
+}
+
+func TestValidateStorageClassIdempotentMerge(t *testing.T) {
+	baseClass := func(params map[string]string) *storage.StorageClass {
+		return &storage.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: "foo", ResourceVersion: "1"},
+			Provisioner: "csi.example.com",
+			Parameters:  params,
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		oldParams map[string]string
+		newParams map[string]string
+		wantError bool
+	}{
+		{
+			name:      "unchanged identity parameter",
+			oldParams: map[string]string{"fsType": "ext4"},
+			newParams: map[string]string{"fsType": "ext4"},
+			wantError: false,
+		},
+		{
+			name:      "changed identity parameter is forbidden",
+			oldParams: map[string]string{"fsType": "ext4"},
+			newParams: map[string]string{"fsType": "xfs"},
+			wantError: true,
+		},
+		{
+			name:      "removed identity parameter is forbidden",
+			oldParams: map[string]string{"fsType": "ext4"},
+			newParams: map[string]string{},
+			wantError: true,
+		},
+		{
+			name:      "added identity parameter is forbidden",
+			oldParams: map[string]string{},
+			newParams: map[string]string{"fsType": "ext4"},
+			wantError: true,
+		},
+		{
+			name:      "changed mutable parameter is allowed",
+			oldParams: map[string]string{"csi.storage.k8s.io/provisioner-secret-name": "a"},
+			newParams: map[string]string{"csi.storage.k8s.io/provisioner-secret-name": "b"},
+			wantError: false,
+		},
+		{
+			name:      "added mutable parameter is allowed",
+			oldParams: map[string]string{},
+			newParams: map[string]string{"csi.storage.k8s.io/provisioner-secret-name": "b"},
+			wantError: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validateStorageClassIdempotentMerge(baseClass(tc.newParams), baseClass(tc.oldParams))
+			if tc.wantError && len(errs) == 0 {
+				t.Errorf("expected validation errors, got none")
+			}
+			if !tc.wantError && len(errs) != 0 {
+				t.Errorf("expected no validation errors, got %v", errs)
+			}
+		})
+	}
+}
+
+func TestValidateStorageClassIdempotentMergeParameterCaps(t *testing.T) {
+	baseClass := func(provisioner string, params map[string]string) *storage.StorageClass {
+		return &storage.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: "foo", ResourceVersion: "1"},
+			Provisioner: provisioner,
+			Parameters:  params,
+		}
+	}
+
+	oversizedValue := make([]byte, maxProvisionerParameterSize+1)
+	for i := range oversizedValue {
+		oversizedValue[i] = 'a'
+	}
+
+	testCases := []struct {
+		name        string
+		provisioner string
+		oldParams   map[string]string
+		newParams   map[string]string
+		wantError   bool
+	}{
+		{
+			name:        "growing a mutable parameter past the total size cap is forbidden",
+			provisioner: "csi.example.com",
+			oldParams:   map[string]string{"csi.storage.k8s.io/provisioner-secret-name": "a"},
+			newParams:   map[string]string{"csi.storage.k8s.io/provisioner-secret-name": string(oversizedValue)},
+			wantError:   true,
+		},
+		{
+			name:        "provisioner name that fails the qualified-name format check is forbidden even when it resolves to the same driver",
+			provisioner: "Not A Valid Name/",
+			oldParams:   map[string]string{},
+			newParams:   map[string]string{},
+			wantError:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validateStorageClassIdempotentMerge(baseClass(tc.provisioner, tc.newParams), baseClass(tc.provisioner, tc.oldParams))
+			if tc.wantError && len(errs) == 0 {
+				t.Errorf("expected validation errors, got none")
+			}
+			if !tc.wantError && len(errs) != 0 {
+				t.Errorf("expected no validation errors, got %v", errs)
+			}
+		})
+	}
+}
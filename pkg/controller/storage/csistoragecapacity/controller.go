@@ -0,0 +1,262 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package csistoragecapacity runs the staleness controller for
+// CSIStorageCapacity objects. An object written once by a crashed or
+// partitioned external-provisioner would otherwise mislead the scheduler
+// forever: this controller marks objects whose LastRefreshTime has fallen
+// too far behind their RefreshPeriod as stale, and optionally
+// garbage-collects them after a grace period.
+package csistoragecapacity
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	storageinformers "k8s.io/client-go/informers/storage/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// StaleCondition is the condition type set to True once a CSIStorageCapacity
+// object has gone unrefreshed for longer than staleAfterMultiple * RefreshPeriod.
+const StaleCondition = "Stale"
+
+// staleAfterMultiple is how many RefreshPeriods may elapse since LastRefreshTime before
+// an object is marked stale. Two full periods tolerates one missed refresh cycle before
+// treating the data as unreliable.
+const staleAfterMultiple = 2
+
+// Controller marks CSIStorageCapacity objects stale once they haven't been refreshed
+// within staleAfterMultiple * their declared RefreshPeriod, and garbage-collects them
+// after GCGracePeriod.
+type Controller struct {
+	client clientset.Interface
+	lister storagelisters.CSIStorageCapacityLister
+	synced cache.InformerSynced
+	queue  workqueue.RateLimitingInterface
+
+	// GCGracePeriod is how long a CSIStorageCapacity may remain Stale before this
+	// controller deletes it. Zero disables garbage collection: the object is still
+	// marked Stale, but left for an operator or the external-provisioner to clean up.
+	GCGracePeriod time.Duration
+}
+
+// NewController creates a Controller that watches CSIStorageCapacity objects through
+// informer and marks/garbage-collects stale ones via client.
+func NewController(client clientset.Interface, informer storageinformers.CSIStorageCapacityInformer, gcGracePeriod time.Duration) *Controller {
+	c := &Controller{
+		client:        client,
+		lister:        informer.Lister(),
+		synced:        informer.Informer().HasSynced,
+		queue:         workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "csistoragecapacity-staleness"),
+		GCGracePeriod: gcGracePeriod,
+	}
+
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    c.enqueue,
+		UpdateFunc: func(old, new interface{}) { c.enqueue(new) },
+	})
+
+	return c
+}
+
+func (c *Controller) enqueue(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return
+	}
+	c.queue.Add(key)
+}
+
+// Run starts workers processing the staleness queue until ctx is cancelled.
+func (c *Controller) Run(ctx context.Context, workers int) {
+	defer c.queue.ShutDown()
+
+	klog.Infof("Starting CSIStorageCapacity staleness controller")
+	defer klog.Infof("Shutting down CSIStorageCapacity staleness controller")
+
+	if !cache.WaitForCacheSync(ctx.Done(), c.synced) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+	<-ctx.Done()
+}
+
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.sync(ctx, key.(string)); err != nil {
+		klog.Errorf("error syncing CSIStorageCapacity %q: %v", key, err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *Controller) sync(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	capacity, err := c.lister.CSIStorageCapacities(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if capacity.RefreshPeriod == nil {
+		// No RefreshPeriod means this object never expires.
+		return nil
+	}
+
+	stale := IsStale(capacity, time.Now())
+	hasCondition := hasStaleCondition(capacity)
+	if stale == hasCondition {
+		return c.maybeGC(ctx, capacity)
+	}
+
+	updated := capacity.DeepCopy()
+	setStaleCondition(updated, stale)
+	// CSIStorageCapacity has no status subresource, so the Stale condition is written
+	// back through a regular Update alongside every other field.
+	if _, err := c.client.StorageV1().CSIStorageCapacities(namespace).Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	return c.maybeGC(ctx, updated)
+}
+
+// maybeGC deletes capacity once it has been Stale for longer than GCGracePeriod. A zero
+// GCGracePeriod disables garbage collection.
+func (c *Controller) maybeGC(ctx context.Context, capacity *storagev1.CSIStorageCapacity) error {
+	if c.GCGracePeriod <= 0 {
+		return nil
+	}
+	cond := findStaleCondition(capacity)
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		return nil
+	}
+	if time.Since(cond.LastTransitionTime.Time) < c.GCGracePeriod {
+		c.queue.AddAfter(capacity.Namespace+"/"+capacity.Name, c.GCGracePeriod-time.Since(cond.LastTransitionTime.Time))
+		return nil
+	}
+	err := c.client.StorageV1().CSIStorageCapacities(capacity.Namespace).Delete(ctx, capacity.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &capacity.UID, ResourceVersion: &capacity.ResourceVersion},
+	})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// IsStale reports whether capacity has gone unrefreshed for longer than
+// staleAfterMultiple * RefreshPeriod as of now. A nil RefreshPeriod is never stale,
+// since the object hasn't opted in to freshness tracking. With a nil LastRefreshTime
+// the period is measured from the object's CreationTimestamp instead.
+func IsStale(capacity *storagev1.CSIStorageCapacity, now time.Time) bool {
+	if capacity.RefreshPeriod == nil {
+		return false
+	}
+	if capacity.LastRefreshTime == nil {
+		// Never refreshed since it started opting in to RefreshPeriod tracking.
+		return now.Sub(capacity.CreationTimestamp.Time) > staleAfterMultiple*capacity.RefreshPeriod.Duration
+	}
+	return now.Sub(capacity.LastRefreshTime.Time) > staleAfterMultiple*capacity.RefreshPeriod.Duration
+}
+
+// SchedulerPredicateCapacity is the view of a CSIStorageCapacity the scheduler's
+// volume-limit predicate needs: whether the object's reported capacity should be
+// trusted. It does not carry the capacity itself. A stale object's capacity is
+// unknown, not zero, so the predicate must not treat it as "full".
+type SchedulerPredicateCapacity struct {
+	// Unknown is true when the capacity should not be relied upon, e.g. because the
+	// object is stale. The predicate should treat an Unknown capacity the same as no
+	// CSIStorageCapacity object at all, rather than assuming zero free space.
+	Unknown bool
+}
+
+// SchedulerPredicateHook is called by the scheduler's CSI volume-limit predicate to
+// decide whether a CSIStorageCapacity object's reported capacity can be trusted.
+func SchedulerPredicateHook(capacity *storagev1.CSIStorageCapacity, now time.Time) SchedulerPredicateCapacity {
+	return SchedulerPredicateCapacity{Unknown: IsStale(capacity, now)}
+}
+
+func hasStaleCondition(capacity *storagev1.CSIStorageCapacity) bool {
+	cond := findStaleCondition(capacity)
+	return cond != nil && cond.Status == metav1.ConditionTrue
+}
+
+func findStaleCondition(capacity *storagev1.CSIStorageCapacity) *metav1.Condition {
+	for i := range capacity.Conditions {
+		if capacity.Conditions[i].Type == StaleCondition {
+			return &capacity.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func setStaleCondition(capacity *storagev1.CSIStorageCapacity, stale bool) {
+	status := metav1.ConditionFalse
+	reason := "Refreshed"
+	message := fmt.Sprintf("last refresh (or creation, if never refreshed) is within %d * spec.refreshPeriod", staleAfterMultiple)
+	if stale {
+		status = metav1.ConditionTrue
+		reason = "RefreshPeriodExceeded"
+		message = fmt.Sprintf("no refresh observed within %d * spec.refreshPeriod", staleAfterMultiple)
+	}
+
+	cond := findStaleCondition(capacity)
+	if cond != nil && cond.Status == status {
+		return
+	}
+
+	newCond := metav1.Condition{
+		Type:               StaleCondition,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	}
+	if cond != nil {
+		*cond = newCond
+		return
+	}
+	capacity.Conditions = append(capacity.Conditions, newCond)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csistoragecapacity
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/kubernetes/pkg/apis/storage"
+	"k8s.io/kubernetes/pkg/apis/storage/validation"
+)
+
+// csiStorageCapacityStrategy implements behavior for CSIStorageCapacity objects
+type csiStorageCapacityStrategy struct {
+	runtime.ObjectTyper
+	names.NameGenerator
+}
+
+// Strategy is the default logic that applies when creating and updating
+// CSIStorageCapacity objects via the REST API.
+var Strategy = csiStorageCapacityStrategy{runtime.ObjectTyper(nil), names.SimpleNameGenerator}
+
+func (csiStorageCapacityStrategy) NamespaceScoped() bool {
+	return true
+}
+
+func (csiStorageCapacityStrategy) PrepareForCreate(ctx context.Context, obj runtime.Object) {
+}
+
+// PrepareForUpdate bumps ObjectMeta.Generation whenever NodeTopology or StorageClassName
+// change, since those fields are the input to CSI GetCapacity and controllers watching
+// CSIStorageCapacity rely on the Generation bump to know they must re-invoke it rather
+// than trusting a stale value.
+func (csiStorageCapacityStrategy) PrepareForUpdate(ctx context.Context, obj, old runtime.Object) {
+	newCapacity := obj.(*storage.CSIStorageCapacity)
+	oldCapacity := old.(*storage.CSIStorageCapacity)
+
+	if validation.CSIStorageCapacityTopologyOrClassChanged(newCapacity, oldCapacity) {
+		newCapacity.Generation = oldCapacity.Generation + 1
+	}
+}
+
+func (csiStorageCapacityStrategy) Validate(ctx context.Context, obj runtime.Object) field.ErrorList {
+	return validation.ValidateCSIStorageCapacity(obj.(*storage.CSIStorageCapacity))
+}
+
+func (csiStorageCapacityStrategy) WarningsOnCreate(ctx context.Context, obj runtime.Object) []string {
+	return nil
+}
+
+func (csiStorageCapacityStrategy) AllowCreateOnUpdate() bool {
+	return false
+}
+
+func (csiStorageCapacityStrategy) AllowUnconditionalUpdate() bool {
+	return false
+}
+
+func (csiStorageCapacityStrategy) Canonicalize(obj runtime.Object) {
+}
+
+func (csiStorageCapacityStrategy) ValidateUpdate(ctx context.Context, obj, old runtime.Object) field.ErrorList {
+	newCapacity := obj.(*storage.CSIStorageCapacity)
+	oldCapacity := old.(*storage.CSIStorageCapacity)
+	return validation.ValidateCSIStorageCapacityUpdate(newCapacity, oldCapacity)
+}
+
+func (csiStorageCapacityStrategy) WarningsOnUpdate(ctx context.Context, obj, old runtime.Object) []string {
+	return nil
+}
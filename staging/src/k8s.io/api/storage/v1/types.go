@@ -0,0 +1,40 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CSIStorageCapacity is the versioned counterpart of
+// k8s.io/kubernetes/pkg/apis/storage.CSIStorageCapacity. It has no status
+// subresource: every field, including Conditions, is updated through a single
+// Update call.
+type CSIStorageCapacity struct {
+	metav1.TypeMeta
+	metav1.ObjectMeta
+
+	NodeTopology      *metav1.LabelSelector
+	StorageClassName  string
+	Capacity          *resource.Quantity
+	MaximumVolumeSize *resource.Quantity
+
+	RefreshPeriod   *metav1.Duration
+	LastRefreshTime *metav1.Time
+	Conditions      []metav1.Condition
+}
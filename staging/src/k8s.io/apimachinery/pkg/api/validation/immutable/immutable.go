@@ -0,0 +1,251 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package immutable walks two instances of the same struct type via
+// reflection and reports every changed leaf that is marked immutable,
+// so that registry strategies no longer have to hand-write a
+// apiequality.Semantic.DeepEqual check per field.
+//
+// Fields opt in with a struct tag:
+//
+//	type Spec struct {
+//		NodeTopology *metav1.LabelSelector `json:"nodeTopology" immutable:"true"`
+//	}
+//
+// Validate walks nested structs, producing a field.ErrorList whose paths mirror
+// the JSON path of each changed field, e.g. a changed label under the field
+// above is reported as
+// `spec.nodeTopology.matchLabels[zone]: Invalid value: "b": field is immutable`.
+// Maps and slices are compared, not walked: an immutable map reports each
+// changed key, an `immutable:"append"` slice each changed index, and an
+// `immutable:"true"` slice the whole slice. Tags on fields of structs held
+// inside an untagged map or slice are not examined. A field with no json tag
+// falls back to an approximation of its JSON name; see jsonFieldName.
+package immutable
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"unicode"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// tagName is the struct tag that marks a field immutable. "true" forbids any
+// change; "append" allows a slice to grow but not to change or drop existing
+// elements (a scaling-only mutation).
+const tagName = "immutable"
+
+// Validate compares newObj and oldObj, which must be pointers to the same
+// struct type, and returns a field.ErrorList describing every change to a
+// field (or nested field) marked with the `immutable:"true"` or
+// `immutable:"append"` struct tag.
+func Validate(newObj, oldObj interface{}, fldPath *field.Path) field.ErrorList {
+	return validate(newObj, oldObj, fldPath, false)
+}
+
+// ValidateStruct is like Validate, but treats every field of newObj/oldObj as immutable
+// regardless of struct tags. Use this when the whole struct is immutable in place (e.g. an
+// existing list entry that may only be added or removed, never modified) and the framework
+// should still recurse to report the specific changed leaf rather than the whole struct.
+func ValidateStruct(newObj, oldObj interface{}, fldPath *field.Path) field.ErrorList {
+	return validate(newObj, oldObj, fldPath, true)
+}
+
+func validate(newObj, oldObj interface{}, fldPath *field.Path, forceImmutable bool) field.ErrorList {
+	newVal := reflect.ValueOf(newObj)
+	oldVal := reflect.ValueOf(oldObj)
+	if newVal.Kind() == reflect.Ptr {
+		newVal = newVal.Elem()
+	}
+	if oldVal.Kind() == reflect.Ptr {
+		oldVal = oldVal.Elem()
+	}
+	return diffStruct(newVal, oldVal, fldPath, forceImmutable)
+}
+
+func diffStruct(newVal, oldVal reflect.Value, fldPath *field.Path, forceImmutable bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+	t := newVal.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			// unexported field
+			continue
+		}
+		tag, hasTag := sf.Tag.Lookup(tagName)
+		immutable := forceImmutable || hasTag
+		childPath := childOf(fldPath, jsonFieldName(sf))
+		allErrs = append(allErrs, diffValue(newVal.Field(i), oldVal.Field(i), childPath, immutable, tag)...)
+	}
+	return allErrs
+}
+
+// childOf returns parent.Child(name), except when parent is the empty root path produced
+// by field.NewPath(""), in which case it returns field.NewPath(name) directly. This lets
+// callers validate a flat, top-level object (no enclosing "spec") without every reported
+// path picking up a stray leading ".".
+func childOf(parent *field.Path, name string) *field.Path {
+	if parent.String() == field.NewPath("").String() {
+		return field.NewPath(name)
+	}
+	return parent.Child(name)
+}
+
+func diffValue(newVal, oldVal reflect.Value, fldPath *field.Path, immutable bool, tag string) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	switch newVal.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if newVal.IsNil() || oldVal.IsNil() {
+			if immutable && newVal.IsNil() != oldVal.IsNil() {
+				allErrs = append(allErrs, immutableErr(fldPath, newVal))
+			}
+			return allErrs
+		}
+		return diffValue(newVal.Elem(), oldVal.Elem(), fldPath, immutable, tag)
+
+	case reflect.Struct:
+		if !immutable {
+			return diffStruct(newVal, oldVal, fldPath, false)
+		}
+		if !apiequality.Semantic.DeepEqual(newVal.Interface(), oldVal.Interface()) {
+			// Recurse so the reported path points at the specific changed leaf
+			// rather than the whole struct.
+			return diffStruct(newVal, oldVal, fldPath, true)
+		}
+		return allErrs
+
+	case reflect.Map:
+		return diffMap(newVal, oldVal, fldPath, immutable)
+
+	case reflect.Slice, reflect.Array:
+		return diffSlice(newVal, oldVal, fldPath, immutable, tag == "append")
+
+	default:
+		if immutable && !apiequality.Semantic.DeepEqual(newVal.Interface(), oldVal.Interface()) {
+			allErrs = append(allErrs, immutableErr(fldPath, newVal))
+		}
+		return allErrs
+	}
+}
+
+func diffMap(newVal, oldVal reflect.Value, fldPath *field.Path, immutable bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !immutable {
+		return allErrs
+	}
+
+	keys := map[string]reflect.Value{}
+	for _, k := range newVal.MapKeys() {
+		keys[fmt.Sprintf("%v", k.Interface())] = k
+	}
+	for _, k := range oldVal.MapKeys() {
+		keys[fmt.Sprintf("%v", k.Interface())] = k
+	}
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		k := keys[name]
+		newEntry := newVal.MapIndex(k)
+		oldEntry := oldVal.MapIndex(k)
+		keyPath := fldPath.Key(name)
+		if !newEntry.IsValid() || !oldEntry.IsValid() {
+			allErrs = append(allErrs, immutableErr(keyPath, newEntry))
+			continue
+		}
+		if !apiequality.Semantic.DeepEqual(newEntry.Interface(), oldEntry.Interface()) {
+			allErrs = append(allErrs, immutableErr(keyPath, newEntry))
+		}
+	}
+	return allErrs
+}
+
+func diffSlice(newVal, oldVal reflect.Value, fldPath *field.Path, immutable, appendOnly bool) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !immutable {
+		return allErrs
+	}
+
+	if appendOnly {
+		if newVal.Len() < oldVal.Len() {
+			allErrs = append(allErrs, immutableErr(fldPath, newVal))
+			return allErrs
+		}
+		for i := 0; i < oldVal.Len(); i++ {
+			if !apiequality.Semantic.DeepEqual(newVal.Index(i).Interface(), oldVal.Index(i).Interface()) {
+				allErrs = append(allErrs, immutableErr(fldPath.Index(i), newVal.Index(i)))
+			}
+		}
+		return allErrs
+	}
+
+	if !apiequality.Semantic.DeepEqual(newVal.Interface(), oldVal.Interface()) {
+		allErrs = append(allErrs, immutableErr(fldPath, newVal))
+	}
+	return allErrs
+}
+
+func immutableErr(fldPath *field.Path, val reflect.Value) *field.Error {
+	var value interface{}
+	if val.IsValid() && val.CanInterface() {
+		value = val.Interface()
+	}
+	return field.Invalid(fldPath, value, "field is immutable")
+}
+
+// jsonFieldName returns the field's external JSON name. Internal API types (e.g.
+// storage.CSINodeDriver, the only type this package is actually used against today) carry
+// no json tags at all, by convention across this codebase, so a tagless field falls back to
+// its Go field name lowercased at the first rune, matching the mechanical Go-field-name-to-
+// camelCase rule the external API's json tags spell out for nearly every field (NodeID ->
+// nodeID, Allocatable -> allocatable). This is an approximation, not a guarantee: a field
+// whose external name diverges from that rule (an internal-only field, or a rename) will
+// report the approximated name instead of the real one.
+func jsonFieldName(sf reflect.StructField) string {
+	tag, ok := sf.Tag.Lookup("json")
+	if ok && tag != "" {
+		name := tag
+		for i, c := range tag {
+			if c == ',' {
+				name = tag[:i]
+				break
+			}
+		}
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return lowerFirstRune(sf.Name)
+}
+
+// lowerFirstRune lowercases just the first rune of s, leaving the rest untouched so that
+// internal acronym casing (e.g. "ID" in "NodeID") carries through as it would in the
+// hand-written camelCase paths used elsewhere in this codebase's validation code.
+func lowerFirstRune(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
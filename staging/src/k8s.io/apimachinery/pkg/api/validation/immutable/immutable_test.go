@@ -0,0 +1,151 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package immutable
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+type testSpec struct {
+	Mutable       string
+	Frozen        string     `immutable:"true"`
+	GrowOnly      []string   `immutable:"append"`
+	Nested        testNested `immutable:"true"`
+	NestedMutable testNested
+}
+
+type testNested struct {
+	Value string
+}
+
+func TestValidate(t *testing.T) {
+	base := func() *testSpec {
+		return &testSpec{
+			Mutable:       "a",
+			Frozen:        "a",
+			GrowOnly:      []string{"a", "b"},
+			Nested:        testNested{Value: "a"},
+			NestedMutable: testNested{Value: "a"},
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		modify    func(s *testSpec)
+		wantError bool
+	}{
+		{
+			name:      "no-op update is valid",
+			modify:    func(s *testSpec) {},
+			wantError: false,
+		},
+		{
+			name:      "changing an untagged field is valid",
+			modify:    func(s *testSpec) { s.Mutable = "b" },
+			wantError: false,
+		},
+		{
+			name:      "changing an immutable:true field is invalid",
+			modify:    func(s *testSpec) { s.Frozen = "b" },
+			wantError: true,
+		},
+		{
+			name:      "appending to an immutable:append slice is valid",
+			modify:    func(s *testSpec) { s.GrowOnly = append(s.GrowOnly, "c") },
+			wantError: false,
+		},
+		{
+			name:      "shrinking an immutable:append slice is invalid",
+			modify:    func(s *testSpec) { s.GrowOnly = s.GrowOnly[:1] },
+			wantError: true,
+		},
+		{
+			name:      "changing an existing element of an immutable:append slice is invalid",
+			modify:    func(s *testSpec) { s.GrowOnly[0] = "z" },
+			wantError: true,
+		},
+		{
+			name:      "changing a field inside an immutable:true struct is invalid",
+			modify:    func(s *testSpec) { s.Nested.Value = "b" },
+			wantError: true,
+		},
+		{
+			name:      "changing a field inside an untagged nested struct is valid",
+			modify:    func(s *testSpec) { s.NestedMutable.Value = "b" },
+			wantError: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			oldObj := base()
+			newObj := base()
+			tc.modify(newObj)
+
+			errs := Validate(newObj, oldObj, field.NewPath("spec"))
+			if tc.wantError && len(errs) == 0 {
+				t.Errorf("expected validation errors, got none")
+			}
+			if !tc.wantError && len(errs) != 0 {
+				t.Errorf("expected no validation errors, got %v", errs)
+			}
+		})
+	}
+}
+
+func TestValidateStruct(t *testing.T) {
+	oldObj := &testNested{Value: "a"}
+
+	if errs := ValidateStruct(&testNested{Value: "a"}, oldObj, field.NewPath("driver")); len(errs) != 0 {
+		t.Errorf("expected no validation errors for an unchanged struct, got %v", errs)
+	}
+	if errs := ValidateStruct(&testNested{Value: "b"}, oldObj, field.NewPath("driver")); len(errs) == 0 {
+		t.Errorf("expected validation errors for a changed field with no immutable tag, since ValidateStruct forces the whole struct immutable")
+	}
+}
+
+func TestValidateRootPathHasNoLeadingDot(t *testing.T) {
+	oldObj := &testNested{Value: "a"}
+	newObj := &testNested{Value: "b"}
+
+	errs := ValidateStruct(newObj, oldObj, field.NewPath(""))
+	if len(errs) != 1 {
+		t.Fatalf("expected exactly one validation error, got %v", errs)
+	}
+	if got, want := errs[0].Field, "value"; got != want {
+		t.Errorf("Field = %q, want %q (no stray leading '.', and lowercased to approximate the JSON name since testNested has no json tags)", got, want)
+	}
+}
+
+type testUntaggedAcronym struct {
+	NodeID string `immutable:"true"`
+}
+
+func TestValidateUntaggedFieldApproximatesCamelCase(t *testing.T) {
+	oldObj := &testUntaggedAcronym{NodeID: "a"}
+	newObj := &testUntaggedAcronym{NodeID: "b"}
+
+	errs := Validate(newObj, oldObj, field.NewPath("spec"))
+	if len(errs) != 1 {
+		t.Fatalf("expected exactly one validation error, got %v", errs)
+	}
+	if got, want := errs[0].Field, "spec.nodeID"; got != want {
+		t.Errorf("Field = %q, want %q (internal types carry no json tags, so the fallback must still lowercase only the leading rune, not collapse the ID acronym)", got, want)
+	}
+}